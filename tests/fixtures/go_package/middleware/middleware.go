@@ -1,19 +1,168 @@
 package middleware
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
 
-type Logger struct {
-	Level string
+// Level controls which calls a Logger actually emits.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a typed key/value pair attached to a log call.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Int builds an integer Field.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String builds a string Field.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds a Field carrying an error under the "error" key.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Logger is the structured, leveled logging surface every package in
+// this app depends on, so callers can swap in a capturing logger for
+// tests without touching call sites.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	SetLevel(level Level)
+}
+
+// TextLogger formats each call as "key=value" pairs written to w.
+type TextLogger struct {
+	level Level
+	w     io.Writer
+}
+
+// NewLogger returns the default TextLogger, writing to stdout at
+// LevelInfo.
+func NewLogger() *TextLogger {
+	return NewTextLogger(os.Stdout)
+}
+
+// NewTextLogger returns a TextLogger writing to w.
+func NewTextLogger(w io.Writer) *TextLogger {
+	return &TextLogger{level: LevelInfo, w: w}
+}
+
+func (l *TextLogger) SetLevel(level Level) {
+	l.level = level
+}
+
+func (l *TextLogger) Debug(msg string, fields ...Field) {
+	l.log(LevelDebug, msg, fields)
+}
+
+func (l *TextLogger) Info(msg string, fields ...Field) {
+	l.log(LevelInfo, msg, fields)
+}
+
+func (l *TextLogger) Warn(msg string, fields ...Field) {
+	l.log(LevelWarn, msg, fields)
+}
+
+func (l *TextLogger) Error(msg string, fields ...Field) {
+	l.log(LevelError, msg, fields)
+}
+
+func (l *TextLogger) log(level Level, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(level.String()), msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	fmt.Fprintln(l.w, b.String())
+}
+
+// JSONLogger emits one JSON object per line to w.
+type JSONLogger struct {
+	level Level
+	w     io.Writer
+}
+
+// NewJSONLogger returns a JSONLogger writing to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{level: LevelInfo, w: w}
+}
+
+func (l *JSONLogger) SetLevel(level Level) {
+	l.level = level
+}
+
+func (l *JSONLogger) Debug(msg string, fields ...Field) {
+	l.log(LevelDebug, msg, fields)
+}
+
+func (l *JSONLogger) Info(msg string, fields ...Field) {
+	l.log(LevelInfo, msg, fields)
 }
 
-func NewLogger() *Logger {
-	return &Logger{Level: "info"}
+func (l *JSONLogger) Warn(msg string, fields ...Field) {
+	l.log(LevelWarn, msg, fields)
 }
 
-func (l *Logger) Info(msg string) {
-	fmt.Println("[INFO]", msg)
+func (l *JSONLogger) Error(msg string, fields ...Field) {
+	l.log(LevelError, msg, fields)
 }
 
-func (l *Logger) Error(msg string) {
-	fmt.Println("[ERROR]", msg)
+func (l *JSONLogger) log(level Level, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+	entry := map[string]interface{}{
+		"level": level.String(),
+		"msg":   msg,
+	}
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok {
+			entry[f.Key] = err.Error()
+			continue
+		}
+		entry[f.Key] = f.Value
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(l.w, string(line))
 }
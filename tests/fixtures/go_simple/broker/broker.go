@@ -0,0 +1,143 @@
+// Package broker provides an in-process publish/subscribe channel for
+// item lifecycle events, so downstream concerns (audit logging, cache
+// invalidation, metrics) can react without DataService knowing about
+// them.
+package broker
+
+import (
+	"sync"
+
+	"myapp/model"
+)
+
+// Event carries an item lifecycle change and when it happened.
+type Event struct {
+	Item      model.Item
+	Timestamp int64
+}
+
+// Subscription is returned by Subscribe; Unsubscribe removes the
+// handler it was given for.
+type Subscription interface {
+	Unsubscribe()
+}
+
+// Broker fans events out to subscribers of a topic.
+type Broker interface {
+	Publish(topic string, evt Event) error
+	Subscribe(topic string, handler func(Event)) (Subscription, error)
+}
+
+type subscription struct {
+	broker *MemoryBroker
+	topic  string
+	id     int
+}
+
+func (s *subscription) Unsubscribe() {
+	s.broker.unsubscribe(s.topic, s.id)
+}
+
+// MemoryBroker is the default Broker: it fans out synchronously on
+// the calling goroutine unless workers is set, in which case each
+// topic gets its own bounded worker pool, so one slow event doesn't
+// hold up delivery of the events queued behind it or of other topics.
+// Within a single event, handlers are still called one after another.
+type MemoryBroker struct {
+	mu      sync.Mutex
+	nextID  int
+	subs    map[string]map[int]func(Event)
+	workers int
+	queues  map[string]chan Event
+}
+
+// NewMemoryBroker returns a MemoryBroker that dispatches synchronously.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subs: make(map[string]map[int]func(Event)),
+	}
+}
+
+// NewMemoryBrokerWithWorkers returns a MemoryBroker that dispatches
+// each topic's events through a pool of workers goroutines, so up to
+// workers events per topic are delivered concurrently. Each event's
+// handlers run serially on one worker, and Publish blocks once the
+// topic's queue of workers events is full.
+func NewMemoryBrokerWithWorkers(workers int) *MemoryBroker {
+	return &MemoryBroker{
+		subs:    make(map[string]map[int]func(Event)),
+		workers: workers,
+		queues:  make(map[string]chan Event),
+	}
+}
+
+func (b *MemoryBroker) Publish(topic string, evt Event) error {
+	if b.workers > 0 {
+		b.queueFor(topic) <- evt
+		return nil
+	}
+
+	b.dispatch(topic, evt)
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(topic string, handler func(Event)) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[int]func(Event))
+	}
+	b.nextID++
+	id := b.nextID
+	b.subs[topic][id] = handler
+
+	return &subscription{broker: b, topic: topic, id: id}, nil
+}
+
+func (b *MemoryBroker) unsubscribe(topic string, id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs[topic], id)
+}
+
+// queueFor lazily starts the bounded worker pool backing topic.
+func (b *MemoryBroker) queueFor(topic string) chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if q, ok := b.queues[topic]; ok {
+		return q
+	}
+
+	q := make(chan Event, b.workers)
+	b.queues[topic] = q
+	for i := 0; i < b.workers; i++ {
+		go b.worker(topic, q)
+	}
+	return q
+}
+
+// worker drains a topic's queue. With more than one worker per topic,
+// events for that topic may be delivered out of publish order.
+func (b *MemoryBroker) worker(topic string, q chan Event) {
+	for evt := range q {
+		b.dispatch(topic, evt)
+	}
+}
+
+// dispatch snapshots topic's current subscribers under the lock, then
+// invokes them outside it so a slow or re-entrant handler can't block
+// Subscribe/Unsubscribe.
+func (b *MemoryBroker) dispatch(topic string, evt Event) {
+	b.mu.Lock()
+	handlers := make([]func(Event), 0, len(b.subs[topic]))
+	for _, h := range b.subs[topic] {
+		handlers = append(handlers, h)
+	}
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(evt)
+	}
+}
@@ -1,53 +1,77 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"myapp/service"
+	"myapp/broker"
 	"myapp/middleware"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/service"
 )
 
 type Handler struct {
 	svc *service.DataService
-	log *middleware.Logger
+	log middleware.Logger
 }
 
-func NewHandler() *Handler {
+func NewHandler(repo repository.Repository, b broker.Broker) *Handler {
 	return &Handler{
-		svc: service.NewDataService(),
+		svc: service.NewDataService(repo, b),
 		log: middleware.NewLogger("handler"),
 	}
 }
 
-func (h *Handler) HandleGet(id int) string {
-	h.log.Info("Getting item", id)
-	result := h.svc.GetItem(id)
+func (h *Handler) HandleGet(ctx context.Context, id int) string {
+	h.log.Info("Getting item", middleware.Int("id", id))
+	result := h.svc.GetItem(ctx, id)
 	if result == "" {
-		h.log.Warn("Item not found", id)
+		h.log.Warn("Item not found", middleware.Int("id", id))
 		return ""
 	}
 	return result
 }
 
-func (h *Handler) HandleCreate(name string) int {
-	h.log.Info("Creating item", 0)
-	id := h.svc.CreateItem(name)
-	h.log.Info("Created item", id)
-	return id
+func (h *Handler) HandleCreate(ctx context.Context, name string) (int, error) {
+	h.log.Info("Creating item", middleware.String("name", name))
+	id, err := h.svc.CreateItem(ctx, name)
+	if err != nil {
+		h.log.Error("Failed to create item", middleware.Err(err))
+		return 0, err
+	}
+	h.log.Info("Created item", middleware.Int("id", id))
+	return id, nil
 }
 
-func (h *Handler) HandleDelete(id int) bool {
-	h.log.Info("Deleting item", id)
-	return h.svc.DeleteItem(id)
+func (h *Handler) HandleDelete(ctx context.Context, id int) (bool, error) {
+	h.log.Info("Deleting item", middleware.Int("id", id))
+	deleted, err := h.svc.DeleteItem(ctx, id)
+	if err != nil {
+		h.log.Error("Failed to delete item", middleware.Int("id", id), middleware.Err(err))
+	}
+	return deleted, err
 }
 
-func (h *Handler) HandleList() []service.ItemRecord {
-	return h.svc.ListItems()
+func (h *Handler) HandleList(ctx context.Context, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool) (model.PaginatedResult, error) {
+	return h.svc.ListItems(ctx, filter, page, pageSize, sortBy, desc, nil)
 }
 
 func main() {
-	h := NewHandler()
-	id := h.HandleCreate("test-item")
+	ctx := context.Background()
+	repo := repository.NewInMemoryRepository()
+	b := broker.NewMemoryBroker()
+	h := NewHandler(repo, b)
+	id, err := h.HandleCreate(ctx, "test-item")
+	if err != nil {
+		fmt.Println("Create error:", err)
+		return
+	}
 	fmt.Println("Created:", id)
-	fmt.Println("Get:", h.HandleGet(id))
-	fmt.Println("All:", h.HandleList())
+	fmt.Println("Get:", h.HandleGet(ctx, id))
+	all, err := h.HandleList(ctx, model.ItemFilter{}, 1, 10, "id", false)
+	if err != nil {
+		fmt.Println("List error:", err)
+		return
+	}
+	fmt.Println("All:", all.Items)
 }
@@ -1,25 +1,173 @@
 package middleware
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
 
-type Logger struct {
+// Level controls which calls a Logger actually emits.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a typed key/value pair attached to a log call.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Int builds an integer Field.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String builds a string Field.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds a Field carrying an error under the "error" key.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Logger is the structured, leveled logging surface every package in
+// this app depends on, so callers can swap in a capturing logger for
+// tests without touching call sites.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	SetLevel(level Level)
+}
+
+// TextLogger formats each call as "key=value" pairs written to w.
+type TextLogger struct {
 	prefix string
+	level  Level
+	w      io.Writer
+}
+
+// NewLogger returns the default TextLogger, writing to stdout under
+// prefix, at LevelInfo.
+func NewLogger(prefix string) *TextLogger {
+	return NewTextLogger(prefix, os.Stdout)
+}
+
+// NewTextLogger returns a TextLogger writing to w under prefix.
+func NewTextLogger(prefix string, w io.Writer) *TextLogger {
+	return &TextLogger{prefix: prefix, level: LevelInfo, w: w}
+}
+
+func (l *TextLogger) SetLevel(level Level) {
+	l.level = level
+}
+
+func (l *TextLogger) Debug(msg string, fields ...Field) {
+	l.log(LevelDebug, msg, fields)
+}
+
+func (l *TextLogger) Info(msg string, fields ...Field) {
+	l.log(LevelInfo, msg, fields)
+}
+
+func (l *TextLogger) Warn(msg string, fields ...Field) {
+	l.log(LevelWarn, msg, fields)
+}
+
+func (l *TextLogger) Error(msg string, fields ...Field) {
+	l.log(LevelError, msg, fields)
+}
+
+func (l *TextLogger) log(level Level, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s: %s", strings.ToUpper(level.String()), l.prefix, msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	fmt.Fprintln(l.w, b.String())
+}
+
+// JSONLogger emits one JSON object per line to w.
+type JSONLogger struct {
+	prefix string
+	level  Level
+	w      io.Writer
+}
+
+// NewJSONLogger returns a JSONLogger writing to w under prefix.
+func NewJSONLogger(prefix string, w io.Writer) *JSONLogger {
+	return &JSONLogger{prefix: prefix, level: LevelInfo, w: w}
+}
+
+func (l *JSONLogger) SetLevel(level Level) {
+	l.level = level
+}
+
+func (l *JSONLogger) Debug(msg string, fields ...Field) {
+	l.log(LevelDebug, msg, fields)
 }
 
-func NewLogger(prefix string) *Logger {
-	return &Logger{prefix: prefix}
+func (l *JSONLogger) Info(msg string, fields ...Field) {
+	l.log(LevelInfo, msg, fields)
 }
 
-func (l *Logger) Info(message string, id int) {
-	fmt.Printf("[INFO] %s: %s (id=%d)\n", l.prefix, message, id)
+func (l *JSONLogger) Warn(msg string, fields ...Field) {
+	l.log(LevelWarn, msg, fields)
 }
 
-func (l *Logger) Warn(message string, id int) {
-	fmt.Printf("[WARN] %s: %s (id=%d)\n", l.prefix, message, id)
+func (l *JSONLogger) Error(msg string, fields ...Field) {
+	l.log(LevelError, msg, fields)
 }
 
-func (l *Logger) Error(message string, err error) {
-	fmt.Printf("[ERROR] %s: %s - %v\n", l.prefix, message, err)
+func (l *JSONLogger) log(level Level, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+	entry := map[string]interface{}{
+		"level":  level.String(),
+		"prefix": l.prefix,
+		"msg":    msg,
+	}
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok {
+			entry[f.Key] = err.Error()
+			continue
+		}
+		entry[f.Key] = f.Value
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(l.w, string(line))
 }
 
 type RequestTimer struct {
@@ -1,13 +1,25 @@
 package repository
 
-import "myapp/model"
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"myapp/model"
+)
+
+// ErrCanceled is returned by FindAll and Query when their iteration
+// aborts because its context was canceled or hit its deadline before
+// finishing.
+var ErrCanceled = errors.New("repository: canceled")
 
 type Repository interface {
-	FindById(id int) (*model.Item, bool)
-	FindAll() []model.Item
-	Save(item model.Item)
-	Delete(id int) bool
-	Count() int
+	FindById(ctx context.Context, id int) (*model.Item, bool)
+	FindAll(ctx context.Context) ([]model.Item, error)
+	Save(ctx context.Context, item model.Item) error
+	Delete(ctx context.Context, id int) (bool, error)
+	Count(ctx context.Context) int
+	Query(ctx context.Context, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool) (model.PaginatedResult, error)
 }
 
 type InMemoryRepository struct {
@@ -20,7 +32,7 @@ func NewInMemoryRepository() *InMemoryRepository {
 	}
 }
 
-func (r *InMemoryRepository) FindById(id int) (*model.Item, bool) {
+func (r *InMemoryRepository) FindById(ctx context.Context, id int) (*model.Item, bool) {
 	item, ok := r.items[id]
 	if !ok {
 		return nil, false
@@ -28,26 +40,124 @@ func (r *InMemoryRepository) FindById(id int) (*model.Item, bool) {
 	return &item, true
 }
 
-func (r *InMemoryRepository) FindAll() []model.Item {
+// FindAll copies out the store, checking ctx.Done() between items so
+// a caller-set deadline can abort a large scan instead of blocking
+// until it finishes.
+func (r *InMemoryRepository) FindAll(ctx context.Context) ([]model.Item, error) {
 	result := make([]model.Item, 0, len(r.items))
 	for _, item := range r.items {
+		select {
+		case <-ctx.Done():
+			return nil, ErrCanceled
+		default:
+		}
 		result = append(result, item)
 	}
-	return result
+	return result, nil
 }
 
-func (r *InMemoryRepository) Save(item model.Item) {
+func (r *InMemoryRepository) Save(ctx context.Context, item model.Item) error {
 	r.items[item.Id] = item
+	return nil
 }
 
-func (r *InMemoryRepository) Delete(id int) bool {
+func (r *InMemoryRepository) Delete(ctx context.Context, id int) (bool, error) {
 	if _, ok := r.items[id]; !ok {
-		return false
+		return false, nil
 	}
 	delete(r.items, id)
-	return true
+	return true, nil
 }
 
-func (r *InMemoryRepository) Count() int {
+func (r *InMemoryRepository) Count(ctx context.Context) int {
 	return len(r.items)
 }
+
+func (r *InMemoryRepository) Query(ctx context.Context, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool) (model.PaginatedResult, error) {
+	items, err := r.FindAll(ctx)
+	if err != nil {
+		return model.PaginatedResult{}, err
+	}
+	return ApplyQuery(ctx, items, filter, page, pageSize, sortBy, desc)
+}
+
+// ApplyQuery runs filter, sort and pagination over items in memory,
+// so every Repository implementation can share the same semantics
+// instead of each re-deriving them. It aborts with ErrCanceled if ctx
+// is done before filtering finishes.
+func ApplyQuery(ctx context.Context, items []model.Item, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool) (model.PaginatedResult, error) {
+	matched := make([]model.Item, 0, len(items))
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			return model.PaginatedResult{}, ErrCanceled
+		default:
+		}
+		if matchesFilter(item, filter) {
+			matched = append(matched, item)
+		}
+	}
+
+	sortItems(matched, sortBy, desc)
+	return Paginate(matched, page, pageSize), nil
+}
+
+// Paginate slices already-filtered-and-sorted items into the
+// requested page. A pageSize <= 0 returns every matching item.
+func Paginate(items []model.Item, page, pageSize int) model.PaginatedResult {
+	total := len(items)
+	start, end := 0, total
+	if pageSize > 0 {
+		start = (page - 1) * pageSize
+		if start < 0 || start >= total {
+			start = total
+		}
+		end = start + pageSize
+		if end > total {
+			end = total
+		}
+	}
+
+	return model.PaginatedResult{
+		Items:    items[start:end],
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+}
+
+func matchesFilter(item model.Item, filter model.ItemFilter) bool {
+	if filter.Category != "" && item.Category != filter.Category {
+		return false
+	}
+	if filter.Active != nil && item.Active != *filter.Active {
+		return false
+	}
+	if item.Price < filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != 0 && item.Price > filter.MaxPrice {
+		return false
+	}
+	return true
+}
+
+func sortItems(items []model.Item, sortBy string, desc bool) {
+	less := func(i, j int) bool {
+		switch sortBy {
+		case "name":
+			return items[i].Name < items[j].Name
+		case "price":
+			return items[i].Price < items[j].Price
+		case "category":
+			return items[i].Category < items[j].Category
+		default:
+			return items[i].Id < items[j].Id
+		}
+	}
+	if desc {
+		orig := less
+		less = func(i, j int) bool { return orig(j, i) }
+	}
+	sort.SliceStable(items, less)
+}
@@ -0,0 +1,166 @@
+package badger
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	"myapp/model"
+	"myapp/repository"
+)
+
+var _ repository.Repository = (*BadgerRepository)(nil)
+
+// BadgerRepository is a Repository backed by an on-disk BadgerDB,
+// keyed by item.Id with values encoded as JSON. Monotonic id
+// resumption across restarts is handled by service.NewDataService,
+// which scans FindAll for the max id on startup; this repository
+// itself keeps no id-related state.
+type BadgerRepository struct {
+	db *badger.DB
+}
+
+// NewBadgerRepository opens (or creates) a BadgerDB at dir.
+func NewBadgerRepository(dir string) (*BadgerRepository, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir))
+	if err != nil {
+		return nil, fmt.Errorf("badger: open %s: %w", dir, err)
+	}
+	return &BadgerRepository{db: db}, nil
+}
+
+// Close releases the underlying BadgerDB.
+func (r *BadgerRepository) Close() error {
+	return r.db.Close()
+}
+
+func itemKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+func isItemKey(key []byte) bool {
+	return len(key) == 8
+}
+
+func (r *BadgerRepository) FindById(ctx context.Context, id int) (*model.Item, bool) {
+	var item model.Item
+	found := false
+	err := r.db.View(func(txn *badger.Txn) error {
+		entry, err := txn.Get(itemKey(id))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		return entry.Value(func(val []byte) error {
+			return json.Unmarshal(val, &item)
+		})
+	})
+	if err != nil || !found {
+		return nil, false
+	}
+	return &item, true
+}
+
+// FindAll iterates every stored item, checking ctx.Done() between
+// entries so a caller-set deadline aborts a large scan instead of
+// reading the whole keyspace.
+func (r *BadgerRepository) FindAll(ctx context.Context) ([]model.Item, error) {
+	var items []model.Item
+	err := r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			select {
+			case <-ctx.Done():
+				return repository.ErrCanceled
+			default:
+			}
+			entry := it.Item()
+			if !isItemKey(entry.Key()) {
+				continue
+			}
+			var item model.Item
+			err := entry.Value(func(val []byte) error {
+				return json.Unmarshal(val, &item)
+			})
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []model.Item{}
+	}
+	return items, nil
+}
+
+func (r *BadgerRepository) Save(ctx context.Context, item model.Item) error {
+	return r.db.Update(func(txn *badger.Txn) error {
+		val, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return txn.Set(itemKey(item.Id), val)
+	})
+}
+
+func (r *BadgerRepository) Delete(ctx context.Context, id int) (bool, error) {
+	deleted := false
+	err := r.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get(itemKey(id)); err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		deleted = true
+		return txn.Delete(itemKey(id))
+	})
+	return deleted, err
+}
+
+// Query loads every stored item into memory and delegates filtering,
+// sorting and pagination to repository.ApplyQuery, which keeps the
+// semantics identical to InMemoryRepository.
+func (r *BadgerRepository) Query(ctx context.Context, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool) (model.PaginatedResult, error) {
+	items, err := r.FindAll(ctx)
+	if err != nil {
+		return model.PaginatedResult{}, err
+	}
+	return repository.ApplyQuery(ctx, items, filter, page, pageSize, sortBy, desc)
+}
+
+// Count scans the item keys without fetching values. If ctx is done
+// mid-scan it stops and returns the number counted so far; Count has
+// no error result, so the cancellation is not reported.
+func (r *BadgerRepository) Count(ctx context.Context) int {
+	count := 0
+	r.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: false})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			select {
+			case <-ctx.Done():
+				return repository.ErrCanceled
+			default:
+			}
+			if isItemKey(it.Item().Key()) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count
+}
@@ -1,63 +1,120 @@
 package service
 
-import "myapp/model"
+import (
+	"context"
+	"sort"
+	"time"
 
+	"myapp/broker"
+	"myapp/model"
+	"myapp/repository"
+	svcsort "myapp/service/sort"
+)
+
+const (
+	TopicItemCreated = "item.created"
+	TopicItemUpdated = "item.updated"
+	TopicItemDeleted = "item.deleted"
+)
+
+// DataService operates on whatever Repository it's given, so callers
+// can pick an in-memory store for tests and a persistent one for
+// production. broker is optional: when nil, lifecycle events simply
+// aren't published.
 type DataService struct {
-	store map[int]model.Item
-	count int
+	repo   repository.Repository
+	broker broker.Broker
+	count  int
 }
 
-type ItemRecord struct {
-	Id   int
-	Name string
+// NewDataService wires svc to repo and resumes the id counter from
+// the highest id already present, so newly created items keep
+// monotonic ids across restarts. b may be nil if no one needs to
+// observe item lifecycle events.
+func NewDataService(repo repository.Repository, b broker.Broker) *DataService {
+	s := &DataService{repo: repo, broker: b}
+	items, err := repo.FindAll(context.Background())
+	if err != nil {
+		return s
+	}
+	for _, item := range items {
+		if item.Id > s.count {
+			s.count = item.Id
+		}
+	}
+	return s
 }
 
-func NewDataService() *DataService {
-	return &DataService{
-		store: make(map[int]model.Item),
+func (s *DataService) publish(topic string, item model.Item) {
+	if s.broker == nil {
+		return
 	}
+	s.broker.Publish(topic, broker.Event{Item: item, Timestamp: time.Now().Unix()})
 }
 
-func (s *DataService) GetItem(id int) string {
-	item, ok := s.store[id]
+func (s *DataService) GetItem(ctx context.Context, id int) string {
+	item, ok := s.repo.FindById(ctx, id)
 	if !ok {
 		return ""
 	}
 	return item.Name
 }
 
-func (s *DataService) CreateItem(name string) int {
+func (s *DataService) CreateItem(ctx context.Context, name string) (int, error) {
 	s.count++
-	s.store[s.count] = model.Item{
+	item := model.Item{
 		Id:     s.count,
 		Name:   name,
 		Active: true,
 	}
-	return s.count
+	if err := s.repo.Save(ctx, item); err != nil {
+		return 0, err
+	}
+	s.publish(TopicItemCreated, item)
+	return s.count, nil
 }
 
-func (s *DataService) DeleteItem(id int) bool {
-	if _, ok := s.store[id]; !ok {
-		return false
+func (s *DataService) DeleteItem(ctx context.Context, id int) (bool, error) {
+	item, ok := s.repo.FindById(ctx, id)
+	if !ok {
+		return false, nil
+	}
+	deleted, err := s.repo.Delete(ctx, id)
+	if err != nil || !deleted {
+		return false, err
 	}
-	delete(s.store, id)
-	return true
+	s.publish(TopicItemDeleted, *item)
+	return true, nil
 }
 
-func (s *DataService) ListItems() []ItemRecord {
-	records := make([]ItemRecord, 0, len(s.store))
-	for _, item := range s.store {
-		records = append(records, ItemRecord{Id: item.Id, Name: item.Name})
+// ListItems delegates filtering, sorting and pagination to the
+// Repository. When cmp is non-nil it overrides sortBy/desc: the
+// Repository's matches are pulled unpaginated, reordered with cmp,
+// then paginated here.
+func (s *DataService) ListItems(ctx context.Context, filter model.ItemFilter, page, pageSize int, sortBy string, desc bool, cmp svcsort.Comparator) (model.PaginatedResult, error) {
+	if cmp == nil {
+		return s.repo.Query(ctx, filter, page, pageSize, sortBy, desc)
 	}
-	return records
+
+	all, err := s.repo.Query(ctx, filter, 1, 0, sortBy, desc)
+	if err != nil {
+		return model.PaginatedResult{}, err
+	}
+	sort.SliceStable(all.Items, func(i, j int) bool {
+		return cmp(all.Items[i], all.Items[j]) < 0
+	})
+	return repository.Paginate(all.Items, page, pageSize), nil
 }
 
-func (s *DataService) UpdateItem(id int, name string) bool {
-	item, ok := s.store[id]
+func (s *DataService) UpdateItem(ctx context.Context, id int, name string) (bool, error) {
+	item, ok := s.repo.FindById(ctx, id)
 	if !ok {
-		return false
+		return false, nil
 	}
 	item.Name = name
-	s.store[id] = item
-	return true
+	if err := s.repo.Save(ctx, *item); err != nil {
+		return false, err
+	}
+	s.publish(TopicItemUpdated, *item)
+	return true, nil
 }
@@ -0,0 +1,78 @@
+// Package sort provides comparator-based ordering for model.Item,
+// following the gostl comparator convention: negative when a sorts
+// before b, zero when they're equal, positive when a sorts after b.
+package sort
+
+import "myapp/model"
+
+// Comparator reports the relative order of a and b.
+type Comparator func(a, b model.Item) int
+
+// ByID orders items by ascending Id.
+func ByID(a, b model.Item) int {
+	switch {
+	case a.Id < b.Id:
+		return -1
+	case a.Id > b.Id:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ByName orders items lexicographically by Name.
+func ByName(a, b model.Item) int {
+	switch {
+	case a.Name < b.Name:
+		return -1
+	case a.Name > b.Name:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ByPrice orders items by ascending Price.
+func ByPrice(a, b model.Item) int {
+	switch {
+	case a.Price < b.Price:
+		return -1
+	case a.Price > b.Price:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ByCategory orders items lexicographically by Category.
+func ByCategory(a, b model.Item) int {
+	switch {
+	case a.Category < b.Category:
+		return -1
+	case a.Category > b.Category:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Reverse flips the order a Comparator produces.
+func Reverse(cmp Comparator) Comparator {
+	return func(a, b model.Item) int {
+		return cmp(b, a)
+	}
+}
+
+// Chain tries each comparator in order, falling through to the next
+// whenever the previous one reports equality, so callers can express
+// "sort by category, then price desc, then name".
+func Chain(cmps ...Comparator) Comparator {
+	return func(a, b model.Item) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}